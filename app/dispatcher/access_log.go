@@ -0,0 +1,57 @@
+package dispatcher
+
+import (
+	"context"
+	"time"
+
+	clog "github.com/xtls/xray-core/app/log"
+	"github.com/xtls/xray-core/common/log"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/session"
+)
+
+// logAccessRecord builds a clog.AccessRecord for a finished dispatch and
+// hands it to the log instance, if access logging is enabled. It is called
+// from Dispatch (see default_dispatcher.go) once a session's link has
+// closed, so that byte counters are final; dispatchErr is whatever the
+// link copy loops returned, nil on a clean close.
+func (d *DefaultDispatcher) logAccessRecord(ctx context.Context, dest net.Destination, tag string, bytesIn, bytesOut int64, dispatchErr error) {
+	inbound := session.InboundFromContext(ctx)
+	if inbound == nil {
+		return
+	}
+
+	rec := &clog.AccessRecord{
+		RemoteAddr: inbound.Source.Address.String(),
+		Timestamp:  time.Now(),
+		BytesIn:    bytesIn,
+		BytesOut:   bytesOut,
+		Tag:        tag,
+	}
+
+	if dest.Address.Family().IsDomain() {
+		rec.Host = dest.Address.Domain()
+	}
+
+	// The HTTP/H2/WS sniffers stash the parsed request line and UA under
+	// well-known Attribute keys (the same extension point SNI sniffing
+	// uses for "tls:sni") rather than a dedicated result type, since most
+	// protocols have nothing to put there.
+	if content := session.ContentFromContext(ctx); content != nil {
+		rec.Proto = content.Protocol
+		rec.Method = content.Attribute("http:method")
+		rec.Path = content.Attribute("http:path")
+		rec.UserAgent = content.Attribute("http:ua")
+		if host := content.Attribute("http:host"); host != "" {
+			rec.Host = host
+		}
+	}
+
+	if dispatchErr != nil {
+		rec.Status = 502
+	} else {
+		rec.Status = 200
+	}
+
+	log.Record(clog.NewAccessMessage(rec))
+}
@@ -0,0 +1,38 @@
+package dispatcher
+
+import (
+	"sync/atomic"
+
+	"github.com/xtls/xray-core/common/buf"
+)
+
+// byteCountingLink wraps the outbound handler's ends of both pipes (the
+// uplink reader and the downlink writer) so the dispatcher can report
+// final byte counts once a session's link closes, without requiring every
+// outbound handler to know about access logging.
+type byteCountingLink struct {
+	reader buf.Reader
+	writer buf.Writer
+
+	in  int64
+	out int64
+}
+
+func (c *byteCountingLink) ReadMultiBuffer() (buf.MultiBuffer, error) {
+	mb, err := c.reader.ReadMultiBuffer()
+	atomic.AddInt64(&c.in, int64(mb.Len()))
+	return mb, err
+}
+
+func (c *byteCountingLink) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	atomic.AddInt64(&c.out, int64(mb.Len()))
+	return c.writer.WriteMultiBuffer(mb)
+}
+
+func (c *byteCountingLink) bytesIn() int64 {
+	return atomic.LoadInt64(&c.in)
+}
+
+func (c *byteCountingLink) bytesOut() int64 {
+	return atomic.LoadInt64(&c.out)
+}
@@ -0,0 +1,47 @@
+package dispatcher
+
+import (
+	"context"
+
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/session"
+	"github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/transport"
+	"github.com/xtls/xray-core/transport/pipe"
+)
+
+// DefaultDispatcher dispatches sessions to an outbound handler picked by
+// the session's outbound tag, tracking byte counts for access logging.
+type DefaultDispatcher struct {
+	ohm core.OutboundHandlerManager
+}
+
+// Dispatch returns a link to the outbound handler named by the session's
+// outbound tag, or to the default handler when that tag is empty or
+// unknown; no router is consulted. Once the handler's Dispatch returns, it
+// records an access log entry with the final byte counts.
+func (d *DefaultDispatcher) Dispatch(ctx context.Context, dest net.Destination) (*transport.Link, error) {
+	ob := session.OutboundFromContext(ctx)
+	tag := ""
+	if ob != nil {
+		tag = ob.Tag
+	}
+
+	handler := d.ohm.GetHandler(tag)
+	if handler == nil {
+		handler = d.ohm.GetDefaultHandler()
+	}
+
+	opt := []pipe.Option{pipe.WithSizeLimit(64 * 1024)}
+	uplinkReader, uplinkWriter := pipe.New(opt...)
+	downlinkReader, downlinkWriter := pipe.New(opt...)
+
+	counter := &byteCountingLink{reader: uplinkReader, writer: downlinkWriter}
+
+	go func() {
+		err := handler.Dispatch(ctx, &transport.Link{Reader: counter, Writer: counter})
+		d.logAccessRecord(ctx, dest, handler.Tag(), counter.bytesIn(), counter.bytesOut(), err)
+	}()
+
+	return &transport.Link{Reader: downlinkReader, Writer: uplinkWriter}, nil
+}
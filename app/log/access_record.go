@@ -0,0 +1,97 @@
+package log
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/xtls/xray-core/common/log"
+)
+
+// AccessRecord describes a single inbound connection for access logging.
+// It is populated by app/dispatcher as a connection's metadata becomes
+// known (sniffed HTTP method/host/path, the routing tag it was dispatched
+// to, and, once the connection closes, byte counts) and handed to the
+// configured access logger.
+type AccessRecord struct {
+	RemoteAddr string
+	Timestamp  time.Time
+	Method     string
+	Host       string
+	Path       string
+	Proto      string
+	Status     int
+	BytesIn    int64
+	BytesOut   int64
+	UserAgent  string
+	Tag        string
+}
+
+// String renders the record in Apache/NCSA vhost-combined log format (the
+// combined format with the virtual host prepended), with the matched
+// routing tag taking the place of the HTTP referer field (Xray has no
+// concept of a referer, and the tag is the more useful value for
+// operators grepping the file).
+func (r *AccessRecord) String() string {
+	host := r.Host
+	if host == "" {
+		host = "-"
+	}
+	method := r.Method
+	if method == "" {
+		method = "-"
+	}
+	path := r.Path
+	if path == "" {
+		path = "-"
+	}
+	proto := r.Proto
+	if proto == "" {
+		proto = "-"
+	}
+	userAgent := r.UserAgent
+	if userAgent == "" {
+		userAgent = "-"
+	}
+	tag := r.Tag
+	if tag == "" {
+		tag = "-"
+	}
+	status := "-"
+	if r.Status > 0 {
+		status = fmt.Sprintf("%d", r.Status)
+	}
+
+	return fmt.Sprintf(
+		`%s %s - - [%s] "%s %s %s" %s %d "%s" "%s"`,
+		host, r.RemoteAddr,
+		r.Timestamp.Format("02/Jan/2006:15:04:05 -0700"),
+		method, path, proto,
+		status, r.BytesOut,
+		tag, userAgent,
+	)
+}
+
+// combinedLogMessage adapts an *AccessRecord to the log.Message interface
+// so it can flow through the normal Handle/Writer pipeline.
+type combinedLogMessage struct {
+	Record *AccessRecord
+}
+
+func (m *combinedLogMessage) String() string {
+	return m.Record.String()
+}
+
+func newCombinedLogWriter(t LogType, path string) (log.Handler, error) {
+	creator, err := log.NewLogWriter(logTypeToWriterType(t), path)
+	if err != nil {
+		return nil, err
+	}
+	return log.NewLogger(creator), nil
+}
+
+// NewAccessMessage wraps rec so it can be passed to Instance.Handle, which
+// will route it to the access logger just like any other access message.
+// It is rendered in Apache combined format whatever AccessLogFormat is set.
+func NewAccessMessage(rec *AccessRecord) log.Message {
+	return &combinedLogMessage{Record: rec}
+}
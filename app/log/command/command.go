@@ -0,0 +1,101 @@
+package command
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	commlog "github.com/xtls/xray-core/common/log"
+)
+
+// RestartLoggerRequest restarts the logger, reopening log files if any are
+// configured.
+type RestartLoggerRequest struct{}
+
+// RestartLoggerResponse is returned by RestartLogger.
+type RestartLoggerResponse struct{}
+
+// RestartLogger restarts the error/access loggers, which is useful after an
+// external log rotation has moved the underlying files away.
+func (s *LoggerServer) RestartLogger(ctx context.Context, request *RestartLoggerRequest) (*RestartLoggerResponse, error) {
+	if err := s.log.Close(); err != nil {
+		return nil, err
+	}
+	if err := s.log.Start(); err != nil {
+		return nil, err
+	}
+	return &RestartLoggerResponse{}, nil
+}
+
+// FollowLogRequest configures server-side filtering applied to the log
+// stream before it is sent to the client, so a noisy instance doesn't
+// flood a `xray logs -f` session.
+type FollowLogRequest struct {
+	// MinSeverity, when set, drops every message less severe than it
+	// (e.g. "warning" keeps Warning and Error, dropping Info/Debug).
+	MinSeverity string
+	// Grep, when set, is a regular expression; only messages whose
+	// formatted text matches it are forwarded.
+	Grep string
+}
+
+// FollowLogResponse carries a single formatted log line.
+type FollowLogResponse struct {
+	Message string
+}
+
+// LoggerService_FollowLogServer is the server-streaming handle used by
+// FollowLog, mirroring the shape grpc-go generates for a streaming RPC.
+type LoggerService_FollowLogServer interface {
+	Send(*FollowLogResponse) error
+	Context() context.Context
+}
+
+// FollowLog streams formatted log lines to the client as they are
+// produced, until the client disconnects or the context is canceled.
+func (s *LoggerServer) FollowLog(request *FollowLogRequest, stream LoggerService_FollowLogServer) error {
+	minSeverity := commlog.Severity_Info
+	if request.MinSeverity != "" {
+		if sev, ok := commlog.SeverityFromString(request.MinSeverity); ok {
+			minSeverity = sev
+		}
+	}
+
+	var grep *regexp.Regexp
+	if request.Grep != "" {
+		re, err := regexp.Compile(request.Grep)
+		if err != nil {
+			return err
+		}
+		grep = re
+	}
+
+	msgs := make(chan commlog.Message, 64)
+	id := s.log.AddFollower(func(msg commlog.Message) {
+		select {
+		case msgs <- msg:
+		default:
+			// Drop the message rather than block the logger when the
+			// client can't keep up.
+		}
+	})
+	defer s.log.RemoveFollower(id)
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case msg := <-msgs:
+			if gm, ok := msg.(*commlog.GeneralMessage); ok && gm.Severity > minSeverity {
+				continue
+			}
+			line := msg.String()
+			if grep != nil && !grep.MatchString(line) {
+				continue
+			}
+			if err := stream.Send(&FollowLogResponse{Message: strings.TrimSuffix(line, "\n")}); err != nil {
+				return err
+			}
+		}
+	}
+}
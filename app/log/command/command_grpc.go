@@ -0,0 +1,130 @@
+package command
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// LoggerServiceServer is the server API for LoggerService.
+type LoggerServiceServer interface {
+	RestartLogger(ctx context.Context, request *RestartLoggerRequest) (*RestartLoggerResponse, error)
+	FollowLog(request *FollowLogRequest, stream LoggerService_FollowLogServer) error
+}
+
+func _LoggerService_RestartLogger_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestartLoggerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoggerServiceServer).RestartLogger(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/xray.app.log.command.LoggerService/RestartLogger",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoggerServiceServer).RestartLogger(ctx, req.(*RestartLoggerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoggerService_FollowLog_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FollowLogRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LoggerServiceServer).FollowLog(m, &loggerServiceFollowLogServer{stream})
+}
+
+type loggerServiceFollowLogServer struct {
+	grpc.ServerStream
+}
+
+func (s *loggerServiceFollowLogServer) Send(resp *FollowLogResponse) error {
+	return s.ServerStream.SendMsg(resp)
+}
+
+// LoggerServiceClient is the client API for LoggerService.
+type LoggerServiceClient interface {
+	RestartLogger(ctx context.Context, in *RestartLoggerRequest, opts ...grpc.CallOption) (*RestartLoggerResponse, error)
+	FollowLog(ctx context.Context, in *FollowLogRequest, opts ...grpc.CallOption) (LoggerService_FollowLogClient, error)
+}
+
+type loggerServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+// NewLoggerServiceClient creates a client for LoggerService bound to cc.
+func NewLoggerServiceClient(cc *grpc.ClientConn) LoggerServiceClient {
+	return &loggerServiceClient{cc}
+}
+
+func (c *loggerServiceClient) RestartLogger(ctx context.Context, in *RestartLoggerRequest, opts ...grpc.CallOption) (*RestartLoggerResponse, error) {
+	out := new(RestartLoggerResponse)
+	if err := c.cc.Invoke(ctx, "/xray.app.log.command.LoggerService/RestartLogger", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LoggerService_FollowLogClient is the client-side handle for the
+// streaming FollowLog RPC.
+type LoggerService_FollowLogClient interface {
+	Recv() (*FollowLogResponse, error)
+	grpc.ClientStream
+}
+
+func (c *loggerServiceClient) FollowLog(ctx context.Context, in *FollowLogRequest, opts ...grpc.CallOption) (LoggerService_FollowLogClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_LoggerService_serviceDesc.Streams[0], "/xray.app.log.command.LoggerService/FollowLog", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &loggerServiceFollowLogClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type loggerServiceFollowLogClient struct {
+	grpc.ClientStream
+}
+
+func (x *loggerServiceFollowLogClient) Recv() (*FollowLogResponse, error) {
+	m := new(FollowLogResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// RegisterLoggerServiceServer registers srv with server under the
+// LoggerService name, analogous to what protoc-gen-go-grpc emits for a
+// service defined in command.proto.
+func RegisterLoggerServiceServer(server *grpc.Server, srv LoggerServiceServer) {
+	server.RegisterService(&_LoggerService_serviceDesc, srv)
+}
+
+var _LoggerService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "xray.app.log.command.LoggerService",
+	HandlerType: (*LoggerServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RestartLogger",
+			Handler:    _LoggerService_RestartLogger_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "FollowLog",
+			Handler:       _LoggerService_FollowLog_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "app/log/command/command.proto",
+}
@@ -0,0 +1,36 @@
+// Package command implements a gRPC service to control the log module of
+// a running Xray instance.
+package command // import "github.com/xtls/xray-core/app/log/command"
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	clog "github.com/xtls/xray-core/app/log"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/core"
+)
+
+// Config is the app config that registers LoggerServer with app/commander.
+type Config struct{}
+
+// LoggerServer is the gRPC service implementation backing LoggerService.
+type LoggerServer struct {
+	log *clog.Instance
+}
+
+// Register implements commander.Service, wiring this server into the
+// shared gRPC server exposed by app/commander.
+func (s *LoggerServer) Register(server *grpc.Server) {
+	RegisterLoggerServiceServer(server, s)
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		v := core.MustFromContext(ctx)
+		return &LoggerServer{
+			log: v.GetFeature((*clog.Instance)(nil)).(*clog.Instance),
+		}, nil
+	}))
+}
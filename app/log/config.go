@@ -0,0 +1,39 @@
+package log
+
+import "github.com/xtls/xray-core/common/log"
+
+// LogType controls how a logger is backed.
+type LogType int32
+
+const (
+	// LogType_None discards messages.
+	LogType_None LogType = iota
+	// LogType_Console writes messages to stdout/stderr.
+	LogType_Console
+	// LogType_File writes messages to a file on disk.
+	LogType_File
+)
+
+// AccessLogFormat names the access (request) log format. Both values open
+// the same kind of access logger, and a line's rendering is set by its
+// message type alone: an AccessRecord (see NewAccessMessage) is always
+// written in Apache/NCSA combined format, a log.AccessMessage always in
+// this app's historical one-line format, whichever value is configured.
+type AccessLogFormat int32
+
+const (
+	// AccessLogFormat_Default names this app's historical one-line format.
+	AccessLogFormat_Default AccessLogFormat = iota
+	// AccessLogFormat_Apache names Apache/NCSA combined log format.
+	AccessLogFormat_Apache
+)
+
+// Config configures the logging app.
+type Config struct {
+	ErrorLogType    LogType
+	ErrorLogPath    string
+	ErrorLogLevel   log.Severity
+	AccessLogType   LogType
+	AccessLogPath   string
+	AccessLogFormat AccessLogFormat
+}
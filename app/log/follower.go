@@ -0,0 +1,66 @@
+package log
+
+import (
+	"sync"
+
+	"github.com/xtls/xray-core/common/log"
+)
+
+// Follower lets external callers receive a copy of every message an
+// Instance logs, without replacing its configured access/error loggers.
+// It backs the `xray logs -f` client and the LoggerService.FollowLog RPC
+// in app/log/command.
+type Follower interface {
+	// AddFollower registers fn to be invoked with every subsequent log
+	// Message. The returned id is used to unregister it later.
+	AddFollower(fn func(log.Message)) int
+	// RemoveFollower unregisters a follower previously returned by
+	// AddFollower. Removing an unknown id is a no-op.
+	RemoveFollower(id int)
+}
+
+// followers is embedded into Instance to implement Follower.
+type followers struct {
+	sync.Mutex
+	nextID int
+	fns    map[int]func(log.Message)
+}
+
+func (f *followers) add(fn func(log.Message)) int {
+	f.Lock()
+	defer f.Unlock()
+	if f.fns == nil {
+		f.fns = make(map[int]func(log.Message))
+	}
+	f.nextID++
+	f.fns[f.nextID] = fn
+	return f.nextID
+}
+
+func (f *followers) remove(id int) {
+	f.Lock()
+	defer f.Unlock()
+	delete(f.fns, id)
+}
+
+func (f *followers) notify(msg log.Message) {
+	f.Lock()
+	fns := make([]func(log.Message), 0, len(f.fns))
+	for _, fn := range f.fns {
+		fns = append(fns, fn)
+	}
+	f.Unlock()
+	for _, fn := range fns {
+		fn(msg)
+	}
+}
+
+// AddFollower implements Follower.
+func (g *Instance) AddFollower(fn func(log.Message)) int {
+	return g.followers.add(fn)
+}
+
+// RemoveFollower implements Follower.
+func (g *Instance) RemoveFollower(id int) {
+	g.followers.remove(id)
+}
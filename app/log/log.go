@@ -0,0 +1,138 @@
+// Package log implements logging facility for Xray.
+package log // import "github.com/xtls/xray-core/app/log"
+
+import (
+	"context"
+	"sync"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/log"
+)
+
+// Instance is a log.Handler that handles logs for Xray.
+type Instance struct {
+	sync.RWMutex
+	config       *Config
+	active       bool
+	accessLogger log.Handler
+	errorLogger  log.Handler
+	followers    followers
+}
+
+// New creates a new log.Instance based on the given config. The access and
+// error loggers are opened by Start, not here, so that Close followed by
+// Start (as LoggerServer.RestartLogger does) reopens them cleanly.
+func New(ctx context.Context, config *Config) (*Instance, error) {
+	return &Instance{
+		config: config,
+		active: false,
+	}, nil
+}
+
+func (g *Instance) initAccessLogger() error {
+	if g.config.AccessLogFormat == AccessLogFormat_Apache {
+		writer, err := newCombinedLogWriter(g.config.AccessLogType, g.config.AccessLogPath)
+		if err != nil {
+			return err
+		}
+		g.accessLogger = writer
+		return nil
+	}
+
+	creator, err := log.NewLogWriter(logTypeToWriterType(g.config.AccessLogType), g.config.AccessLogPath)
+	if err != nil {
+		return err
+	}
+	g.accessLogger = log.NewLogger(creator)
+	return nil
+}
+
+func (g *Instance) initErrorLogger() error {
+	creator, err := log.NewLogWriter(logTypeToWriterType(g.config.ErrorLogType), g.config.ErrorLogPath)
+	if err != nil {
+		return err
+	}
+	g.errorLogger = log.NewLogger(creator)
+	return nil
+}
+
+func logTypeToWriterType(t LogType) log.WriterType {
+	switch t {
+	case LogType_Console:
+		return log.WriterType_Console
+	case LogType_File:
+		return log.WriterType_File
+	default:
+		return log.WriterType_None
+	}
+}
+
+// Type implements common.HasType.
+func (*Instance) Type() interface{} {
+	return (*Instance)(nil)
+}
+
+// Start implements common.Runnable.Start(). It (re-)opens the access and
+// error loggers, so calling Start after Close reopens their underlying
+// files rather than resuming with already-closed writers; this is what
+// lets LoggerServer.RestartLogger pick up a log rotation.
+func (g *Instance) Start() error {
+	g.Lock()
+	defer g.Unlock()
+
+	if err := g.initAccessLogger(); err != nil {
+		return errors.New("failed to initialize access logger").Base(err)
+	}
+	if err := g.initErrorLogger(); err != nil {
+		return errors.New("failed to initialize error logger").Base(err)
+	}
+
+	g.active = true
+
+	log.RegisterHandler(g)
+
+	return nil
+}
+
+// Close implements common.Closable.Close().
+func (g *Instance) Close() error {
+	g.Lock()
+	defer g.Unlock()
+
+	g.active = false
+
+	common.Close(g.accessLogger) // nolint: errcheck
+	common.Close(g.errorLogger)  // nolint: errcheck
+
+	return nil
+}
+
+// Handle implements log.Handler.
+func (g *Instance) Handle(msg log.Message) {
+	g.RLock()
+	defer g.RUnlock()
+
+	if !g.active {
+		return
+	}
+
+	switch msg := msg.(type) {
+	case *log.AccessMessage, *combinedLogMessage:
+		if g.accessLogger != nil {
+			g.accessLogger.Handle(msg)
+		}
+	default:
+		if g.errorLogger != nil {
+			g.errorLogger.Handle(msg)
+		}
+	}
+
+	g.followers.notify(msg)
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		return New(ctx, config.(*Config))
+	}))
+}
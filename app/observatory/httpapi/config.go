@@ -0,0 +1,54 @@
+// Package httpapi exposes a small HTTP server reporting per-outbound
+// health, suitable for a container orchestrator's liveness/readiness
+// probes and for Prometheus-style scraping.
+package httpapi // import "github.com/xtls/xray-core/app/observatory/httpapi"
+
+import (
+	"context"
+	"time"
+
+	"github.com/xtls/xray-core/common"
+)
+
+// Config configures the httpapi app.
+type Config struct {
+	// Listen is the HTTP bind address, e.g. "127.0.0.1:18080".
+	Listen string
+	// OutboundTags lists which outbounds to probe and report on. It is
+	// required: outbound.Manager has no API to enumerate every handler it
+	// holds, so there is no way to discover "every outbound" at runtime.
+	OutboundTags []string
+	// ProbeURL is the target HEAD-requested through each outbound.
+	ProbeURL string
+	// Interval between probe rounds. Default 10s.
+	Interval time.Duration
+	// Timeout for a single probe. Default 5s.
+	Timeout time.Duration
+}
+
+func (c *Config) interval() time.Duration {
+	if c.Interval <= 0 {
+		return 10 * time.Second
+	}
+	return c.Interval
+}
+
+func (c *Config) timeout() time.Duration {
+	if c.Timeout <= 0 {
+		return 5 * time.Second
+	}
+	return c.Timeout
+}
+
+func (c *Config) probeURL() string {
+	if c.ProbeURL == "" {
+		return "https://www.google.com/"
+	}
+	return c.ProbeURL
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		return New(ctx, config.(*Config))
+	}))
+}
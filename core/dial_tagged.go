@@ -0,0 +1,58 @@
+package core
+
+import (
+	"context"
+
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/net/cnc"
+	"github.com/xtls/xray-core/common/session"
+	"github.com/xtls/xray-core/transport"
+	"github.com/xtls/xray-core/transport/pipe"
+)
+
+// DialTagged dials dest directly through the outbound handler identified by
+// tag, bypassing the router entirely. This is stronger than stuffing a
+// forced tag into the session's context and calling Dial: the router still
+// runs in that case and can select a different outbound the moment one of
+// its rules also matches dest, silently ignoring the forced tag. Calling
+// the handler's Dispatch directly makes the returned connection use that
+// outbound unconditionally, which is what tooling like `xray ping` needs
+// to build a real per-outbound latency matrix.
+func DialTagged(ctx context.Context, inst *Instance, dest net.Destination, tag string) (net.Conn, error) {
+	if tag == "" {
+		return Dial(ctx, inst, dest)
+	}
+
+	om, ok := inst.GetFeature((*OutboundHandlerManager)(nil)).(OutboundHandlerManager)
+	if !ok {
+		return nil, errors.New("outbound handler manager not available")
+	}
+	handler := om.GetHandler(tag)
+	if handler == nil {
+		return nil, errors.New("unknown outbound tag: ", tag)
+	}
+
+	// The handler reads its dial target off the context, not off an
+	// argument to Dispatch, so it has to be set here: without it,
+	// outbounds read session.OutboundsFromContext(ctx)[len-1] and either
+	// panic on the empty slice or dial the zero Destination.
+	ctx = session.ContextWithOutbounds(ctx, []*session.Outbound{{
+		Target: dest,
+		Tag:    tag,
+	}})
+
+	opt := []pipe.Option{pipe.WithSizeLimit(64 * 1024)}
+	uplinkReader, uplinkWriter := pipe.New(opt...)
+	downlinkReader, downlinkWriter := pipe.New(opt...)
+
+	go handler.Dispatch(ctx, &transport.Link{Reader: uplinkReader, Writer: downlinkWriter})
+
+	var readerOpt cnc.ConnectionOption
+	if dest.Network == net.Network_TCP {
+		readerOpt = cnc.ConnectionOutputMulti(downlinkReader)
+	} else {
+		readerOpt = cnc.ConnectionOutputMultiUDP(downlinkReader)
+	}
+	return cnc.NewConnection(cnc.ConnectionInputMulti(uplinkWriter), readerOpt), nil
+}
@@ -0,0 +1,175 @@
+package core
+
+import (
+	"context"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/buf"
+	"github.com/xtls/xray-core/common/errors"
+	xnet "github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/session"
+	"github.com/xtls/xray-core/transport"
+	"github.com/xtls/xray-core/transport/pipe"
+)
+
+// DialUDP dials dest (which must be a UDP destination) through tag,
+// bypassing the router the same way DialTagged does, and returns a
+// net.PacketConn rather than a net.Conn. This is needed by callers such as
+// quic-go that read/write whole datagrams instead of a byte stream. Unlike
+// DialTagged, an empty tag selects the default outbound handler rather
+// than routing normally.
+func DialUDP(ctx context.Context, inst *Instance, dest xnet.Destination, tag string) (net.PacketConn, error) {
+	om, ok := inst.GetFeature((*OutboundHandlerManager)(nil)).(OutboundHandlerManager)
+	if !ok {
+		return nil, errors.New("outbound handler manager not available")
+	}
+	handler := om.GetDefaultHandler()
+	if tag != "" {
+		handler = om.GetHandler(tag)
+	}
+	if handler == nil {
+		return nil, errors.New("unknown outbound tag: ", tag)
+	}
+
+	// See DialTagged: the handler reads its dial target off the context,
+	// so it has to be set here or every datagram dials the zero
+	// Destination.
+	ctx = session.ContextWithOutbounds(ctx, []*session.Outbound{{
+		Target: dest,
+		Tag:    tag,
+	}})
+
+	opt := []pipe.Option{pipe.WithSizeLimit(64 * 1024)}
+	uplinkReader, uplinkWriter := pipe.New(opt...)
+	downlinkReader, downlinkWriter := pipe.New(opt...)
+
+	go handler.Dispatch(ctx, &transport.Link{Reader: uplinkReader, Writer: downlinkWriter})
+
+	return &udpPacketConn{
+		remote: &net.UDPAddr{IP: net.ParseIP(dest.Address.String()), Port: int(dest.Port)},
+		reader: downlinkReader,
+		writer: uplinkWriter,
+	}, nil
+}
+
+// udpPacketConn adapts a pair of buf.Reader/buf.Writer pipe endpoints
+// dispatched to a single UDP destination into a net.PacketConn, which is
+// the shape quic-go (and similar datagram-oriented libraries) expect.
+type udpPacketConn struct {
+	remote net.Addr
+	reader buf.Reader
+	writer buf.Writer
+
+	mu            sync.Mutex
+	readDeadline  time.Time
+	writeDeadline time.Time
+}
+
+func (c *udpPacketConn) ReadFrom(p []byte) (int, net.Addr, error) {
+	c.mu.Lock()
+	deadline := c.readDeadline
+	c.mu.Unlock()
+
+	if deadline.IsZero() {
+		return c.readFrom(p)
+	}
+
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+
+	type result struct {
+		n    int
+		addr net.Addr
+		err  error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		n, addr, err := c.readFrom(p)
+		ch <- result{n, addr, err}
+	}()
+
+	select {
+	case r := <-ch:
+		return r.n, r.addr, r.err
+	case <-timer.C:
+		return 0, nil, os.ErrDeadlineExceeded
+	}
+}
+
+func (c *udpPacketConn) readFrom(p []byte) (int, net.Addr, error) {
+	mb, err := c.reader.ReadMultiBuffer()
+	if err != nil {
+		return 0, nil, err
+	}
+	defer buf.ReleaseMulti(mb)
+	n := 0
+	for _, b := range mb {
+		n += copy(p[n:], b.Bytes())
+		if n >= len(p) {
+			break
+		}
+	}
+	return n, c.remote, nil
+}
+
+func (c *udpPacketConn) WriteTo(p []byte, addr net.Addr) (int, error) {
+	c.mu.Lock()
+	deadline := c.writeDeadline
+	c.mu.Unlock()
+	if !deadline.IsZero() && time.Now().After(deadline) {
+		return 0, os.ErrDeadlineExceeded
+	}
+
+	target := addr
+	if target == nil {
+		target = c.remote
+	}
+	dest := xnet.DestinationFromAddr(target)
+
+	b := buf.New()
+	if _, err := b.Write(p); err != nil {
+		return 0, err
+	}
+	// The pipe carries every datagram for this conn's lifetime, so each
+	// buffer has to carry its own destination; the outbound can't route
+	// it otherwise.
+	b.UDP = &dest
+	if err := c.writer.WriteMultiBuffer(buf.MultiBuffer{b}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (c *udpPacketConn) Close() error {
+	common.Close(c.reader) // nolint: errcheck
+	common.Close(c.writer) // nolint: errcheck
+	return nil
+}
+
+func (c *udpPacketConn) LocalAddr() net.Addr { return &net.UDPAddr{} }
+
+func (c *udpPacketConn) SetDeadline(t time.Time) error {
+	c.mu.Lock()
+	c.readDeadline = t
+	c.writeDeadline = t
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *udpPacketConn) SetReadDeadline(t time.Time) error {
+	c.mu.Lock()
+	c.readDeadline = t
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *udpPacketConn) SetWriteDeadline(t time.Time) error {
+	c.mu.Lock()
+	c.writeDeadline = t
+	c.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,49 @@
+package conf
+
+import (
+	clog "github.com/xtls/xray-core/app/log"
+	"github.com/xtls/xray-core/common/log"
+)
+
+// LogConfig is the JSON-facing representation of the "log" config block.
+type LogConfig struct {
+	AccessLog       string `json:"access"`
+	ErrorLog        string `json:"error"`
+	LogLevel        string `json:"loglevel"`
+	// AccessLogFormat sets clog.Config.AccessLogFormat: "apache" selects
+	// clog.AccessLogFormat_Apache, any other value (default "default")
+	// clog.AccessLogFormat_Default. The setting does not change how lines
+	// are rendered; see clog.AccessLogFormat.
+	AccessLogFormat string `json:"accessLogFormat"`
+}
+
+// Build converts this JSON config into the app/log proto config.
+func (c *LogConfig) Build() *clog.Config {
+	config := &clog.Config{
+		ErrorLogType:  clog.LogType_Console,
+		AccessLogType: clog.LogType_Console,
+	}
+
+	if c.AccessLog != "" {
+		config.AccessLogType = clog.LogType_File
+		config.AccessLogPath = c.AccessLog
+	}
+	if c.ErrorLog != "" {
+		config.ErrorLogType = clog.LogType_File
+		config.ErrorLogPath = c.ErrorLog
+	}
+	if c.LogLevel != "" {
+		if sev, ok := log.SeverityFromString(c.LogLevel); ok {
+			config.ErrorLogLevel = sev
+		}
+	}
+	if c.AccessLogFormat == "apache" {
+		config.AccessLogFormat = clog.AccessLogFormat_Apache
+	}
+	if c.LogLevel == "none" {
+		config.ErrorLogType = clog.LogType_None
+		config.AccessLogType = clog.LogType_None
+	}
+
+	return config
+}
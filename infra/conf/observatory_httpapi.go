@@ -0,0 +1,43 @@
+package conf
+
+import (
+	"time"
+
+	"github.com/xtls/xray-core/app/observatory/httpapi"
+)
+
+// HTTPAPIConfig is the JSON-facing representation of the "observatory"
+// httpapi config block.
+type HTTPAPIConfig struct {
+	Listen       string   `json:"listen"`
+	OutboundTags []string `json:"outboundTags"`
+	ProbeURL     string   `json:"probeUrl"`
+	Interval     string   `json:"interval"`
+	Timeout      string   `json:"timeout"`
+}
+
+// Build converts this JSON config into the app/observatory/httpapi config.
+func (c *HTTPAPIConfig) Build() (*httpapi.Config, error) {
+	config := &httpapi.Config{
+		Listen:       c.Listen,
+		OutboundTags: c.OutboundTags,
+		ProbeURL:     c.ProbeURL,
+	}
+
+	if c.Interval != "" {
+		d, err := time.ParseDuration(c.Interval)
+		if err != nil {
+			return nil, err
+		}
+		config.Interval = d
+	}
+	if c.Timeout != "" {
+		d, err := time.ParseDuration(c.Timeout)
+		if err != nil {
+			return nil, err
+		}
+		config.Timeout = d
+	}
+
+	return config, nil
+}
@@ -0,0 +1,89 @@
+package all
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"google.golang.org/grpc"
+
+	logService "github.com/xtls/xray-core/app/log/command"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdLogs = &base.Command{
+	UsageLine: `{{.Exec}} logs -f [-s 127.0.0.1:8080] [-level=warning] [-grep=pattern]`,
+	Short:     `Stream live logs from a running Xray instance`,
+	Long: `
+Logs attaches to a running Xray instance (started with the
+API app/commander enabled) and prints its log lines as they happen,
+until interrupted with Ctrl-C.
+
+The -s flag sets the API server address, in the same form used by the
+other "xray api" commands.
+
+The -level flag filters out error-log messages less severe than it,
+e.g. -level=warning keeps only warning and error messages. Access log
+lines are not filtered by level. Default "info".
+
+The -grep flag is a regular expression; only lines matching it are
+printed. Filtering happens on the server, so it does not need to be
+applied again on the client.
+`,
+}
+
+func init() {
+	cmdLogs.Run = executeLogs
+}
+
+var (
+	logsServerAddr = cmdLogs.Flag.String("s", "127.0.0.1:8080", "API server address")
+	logsFollow     = cmdLogs.Flag.Bool("f", false, "Follow the log stream")
+	logsLevel      = cmdLogs.Flag.String("level", "info", "Minimum severity to show")
+	logsGrep       = cmdLogs.Flag.String("grep", "", "Only show lines matching this regular expression")
+)
+
+func executeLogs(cmd *base.Command, args []string) {
+	if !*logsFollow {
+		base.Fatalf("logs: -f is required (only streaming mode is supported)")
+	}
+
+	conn, err := grpc.Dial(*logsServerAddr, grpc.WithInsecure())
+	if err != nil {
+		base.Fatalf("failed to connect to %s: %s", *logsServerAddr, err)
+	}
+	defer conn.Close()
+
+	client := logService.NewLoggerServiceClient(conn)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt)
+	go func() {
+		<-sigCh
+		cancel()
+	}()
+
+	stream, err := client.FollowLog(ctx, &logService.FollowLogRequest{
+		MinSeverity: *logsLevel,
+		Grep:        *logsGrep,
+	})
+	if err != nil {
+		base.Fatalf("failed to start log stream: %s", err)
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF || ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			base.Fatalf("log stream closed: %s", err)
+		}
+		fmt.Println(resp.Message)
+	}
+}
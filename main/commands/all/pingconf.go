@@ -3,6 +3,8 @@ package all
 import (
 	"context"
 	"crypto/tls"
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"log"
 	"net"
@@ -12,6 +14,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/xtls/xray-core/common/cmdarg"
@@ -25,20 +28,43 @@ import (
 )
 
 var cmdPing = &base.Command{
-	UsageLine: `{{.Exec}} ping [-u https://www.google.com/] [-c config.json] [-t 10s]`,
+	UsageLine: `{{.Exec}} ping [-u https://www.google.com/] [-c config.json] [-t 10s] [-outbound tag]`,
 	Short:     `Ping Xray with config and exit`,
 	Long: `
-The -config=file, -c=file flags set the config files for 
+The -config=file, -c=file flags set the config files for
 Xray. Multiple assign is accepted.
 
 The -confdir=dir flag sets a dir with multiple json config
 
-The -format=json flag sets the format of config files. 
+The -format=json flag sets the format of config files.
 Default "auto".
 
-The -u for target url of ping
+The -u for target url of ping. May be repeated to test more than
+one target.
+
+The -outbound for the tag of an outbound to send the probe through,
+bypassing routing. May be repeated; the probe is run once per
+(target, outbound) combination. When omitted, the probe is routed
+normally.
 
 The -t for connection timeout
+
+The -proto=http|tcp|tls|udp|quic|dns selects what a probe measures.
+"http" (default) issues an HTTPS HEAD as before. "tcp"/"tls" time a
+raw handshake through the instance, useful for proxy stacks where an
+HTTP HEAD is a poor test (e.g. TLS-in-TLS). "udp" sends a single
+datagram. "quic" completes a QUIC handshake over UDP. "dns" issues a
+lookup through the instance's DNS app. For every mode but "http",
+-u is the dialed address ("host:port" for tcp/tls/udp/quic, a domain
+name for dns) rather than a URL. "dns" ignores -outbound, and "quic"
+without -outbound uses the default outbound instead of routing.
+
+The -count=N flag repeats each probe N times (default 1) and reports
+min/avg/max/p95 latency and loss. The -interval=D flag sets the gap
+between repeats (default 1s).
+
+The -outputformat=table|json|csv selects how the results of a
+multi-target/multi-outbound run are printed. Default "table".
 `,
 }
 
@@ -47,16 +73,23 @@ func init() {
 }
 
 var (
-	configFiles cmdarg.Arg
-	configDir   string
-	format      = cmdPing.Flag.String("format", "auto", "Format of input file.")
-	pingUrl     = cmdPing.Flag.String("u", "https://www.google.com/", "")
-	pingTimeout = cmdPing.Flag.String("t", "10s", "")
+	configFiles  cmdarg.Arg
+	configDir    string
+	format       = cmdPing.Flag.String("format", "auto", "Format of input file.")
+	pingUrls     cmdarg.Arg
+	outboundTags cmdarg.Arg
+	pingTimeout  = cmdPing.Flag.String("t", "10s", "")
+	outputFormat = cmdPing.Flag.String("outputformat", "table", "Output format of ping results: table|json|csv")
+	pingProto    = cmdPing.Flag.String("proto", "http", "Probe protocol: http|tcp|tls|udp|quic|dns")
+	pingCount    = cmdPing.Flag.Int("count", 1, "Number of probes to send per target/outbound")
+	pingInterval = cmdPing.Flag.String("interval", "1s", "Gap between repeated probes")
 
 	_ = func() bool {
 		cmdPing.Flag.Var(&configFiles, "config", "Config path for Xray.")
 		cmdPing.Flag.Var(&configFiles, "c", "Short alias of -config")
 		cmdPing.Flag.StringVar(&configDir, "confdir", "", "A dir with multiple json config")
+		cmdPing.Flag.Var(&pingUrls, "u", "Target url of ping. Repeatable.")
+		cmdPing.Flag.Var(&outboundTags, "outbound", "Tag of an outbound to probe. Repeatable.")
 
 		return true
 	}()
@@ -162,6 +195,21 @@ func getConfigFormat() string {
 	return f
 }
 
+// pingResult holds the outcome of probing a single (target, outbound) pair,
+// possibly across several repeated samples (see -count).
+type pingResult struct {
+	Target   string `json:"target"`
+	Outbound string `json:"outbound,omitempty"`
+	Status   string `json:"status"`
+	Sent     int    `json:"sent"`
+	Lost     int    `json:"lost"`
+	MinMs    int64  `json:"minMs"`
+	AvgMs    int64  `json:"avgMs"`
+	MaxMs    int64  `json:"maxMs"`
+	P95Ms    int64  `json:"p95Ms"`
+	Error    string `json:"error,omitempty"`
+}
+
 func measureOutboundDelay() (string, error) {
 	configFiles := getConfigFilePath(true)
 
@@ -185,24 +233,141 @@ func measureOutboundDelay() (string, error) {
 		return "", err
 	}
 
-	err = inst.Start()
+	if err := inst.Start(); err != nil {
+		return "", err
+	}
+	defer inst.Close()
+
+	targets := pingUrls
+	if len(targets) == 0 {
+		targets = cmdarg.Arg{"https://www.google.com/"}
+	}
+	tags := outboundTags
+	if len(tags) == 0 {
+		tags = cmdarg.Arg{""}
+	}
+
+	connectTimeout, err := time.ParseDuration(*pingTimeout)
+	if err != nil {
+		return "", err
+	}
+	interval, err := time.ParseDuration(*pingInterval)
 	if err != nil {
 		return "", err
 	}
-	delay, err := measureInstDelay(context.Background(), inst, *pingUrl, *pingTimeout)
-	inst.Close()
-	return delay, err
+	count := *pingCount
+	if count < 1 {
+		count = 1
+	}
+
+	results := make([]pingResult, 0, len(targets)*len(tags))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, target := range targets {
+		for _, tag := range tags {
+			wg.Add(1)
+			go func(target, tag string) {
+				defer wg.Done()
+				r := probeTarget(inst, target, tag, count, interval, connectTimeout)
+				mu.Lock()
+				results = append(results, r)
+				mu.Unlock()
+			}(target, tag)
+		}
+	}
+	wg.Wait()
+
+	return formatPingResults(results)
 }
 
-func measureInstDelay(ctx context.Context, inst *core.Instance, url string, timeout string) (string, error) {
+// probeTarget runs count probes of *pingProto against target through tag,
+// spaced interval apart, and summarizes them into a single pingResult.
+func probeTarget(inst *core.Instance, target, tag string, count int, interval, timeout time.Duration) pingResult {
+	samples := make([]time.Duration, 0, count)
+	var status, lastErr string
+	for i := 0; i < count; i++ {
+		delay, st, err := probeOnce(context.Background(), inst, strings.ToLower(*pingProto), target, tag, timeout)
+		if err != nil {
+			lastErr = err.Error()
+		} else {
+			status = st
+			samples = append(samples, delay)
+		}
+		if i < count-1 {
+			time.Sleep(interval)
+		}
+	}
+
+	stats := summarize(samples, count, status, lastErr)
+	return pingResult{
+		Target:   target,
+		Outbound: tag,
+		Status:   stats.Status,
+		Sent:     stats.Sent,
+		Lost:     stats.Lost,
+		MinMs:    stats.Min.Milliseconds(),
+		AvgMs:    stats.Avg.Milliseconds(),
+		MaxMs:    stats.Max.Milliseconds(),
+		P95Ms:    stats.P95.Milliseconds(),
+		Error:    stats.Error,
+	}
+}
+
+func formatPingResults(results []pingResult) (string, error) {
+	switch strings.ToLower(*outputFormat) {
+	case "json":
+		b, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	case "csv":
+		var sb strings.Builder
+		w := csv.NewWriter(&sb)
+		if err := w.Write([]string{"target", "outbound", "status", "sent", "lost", "min_ms", "avg_ms", "max_ms", "p95_ms", "error"}); err != nil {
+			return "", err
+		}
+		for _, r := range results {
+			if err := w.Write([]string{
+				r.Target, r.Outbound, r.Status,
+				fmt.Sprintf("%d", r.Sent), fmt.Sprintf("%d", r.Lost),
+				fmt.Sprintf("%d", r.MinMs), fmt.Sprintf("%d", r.AvgMs),
+				fmt.Sprintf("%d", r.MaxMs), fmt.Sprintf("%d", r.P95Ms), r.Error,
+			}); err != nil {
+				return "", err
+			}
+		}
+		w.Flush()
+		return strings.TrimRight(sb.String(), "\n"), w.Error()
+	default:
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "%-40s %-16s %-16s %6s %10s %10s %10s %10s %s\n",
+			"TARGET", "OUTBOUND", "STATUS", "LOSS", "MIN", "AVG", "MAX", "P95", "ERROR")
+		for _, r := range results {
+			outbound := r.Outbound
+			if outbound == "" {
+				outbound = "(default)"
+			}
+			loss := 0
+			if r.Sent > 0 {
+				loss = r.Lost * 100 / r.Sent
+			}
+			fmt.Fprintf(&sb, "%-40s %-16s %-16s %5d%% %9dms %9dms %9dms %9dms %s\n",
+				r.Target, outbound, r.Status, loss, r.MinMs, r.AvgMs, r.MaxMs, r.P95Ms, r.Error)
+		}
+		return strings.TrimRight(sb.String(), "\n"), nil
+	}
+}
+
+func measureInstDelay(ctx context.Context, inst *core.Instance, url string, outboundTag string, timeout string) (time.Duration, string, error) {
 	connectTimeout, err := time.ParseDuration(timeout)
 	if err != nil {
-		return "", err
+		return 0, "", err
 	}
 
 	req, err := http.NewRequestWithContext(ctx, "HEAD", url, nil)
 	if err != nil {
-		return "", err
+		return 0, "", err
 	}
 	req.Header.Add("Connection", "close")
 	req.Header.Add("Accept-Encoding", "gzip")
@@ -218,7 +383,7 @@ func measureInstDelay(ctx context.Context, inst *core.Instance, url string, time
 			if err != nil {
 				return nil, err
 			}
-			return core.Dial(ctx, inst, dest)
+			return core.DialTagged(ctx, inst, dest, outboundTag)
 		},
 	}
 
@@ -230,13 +395,13 @@ func measureInstDelay(ctx context.Context, inst *core.Instance, url string, time
 	start := time.Now()
 	resp, err := c.Do(req)
 	if err != nil {
-		return "", err
+		return 0, "", err
 	}
-	timeElapsed := time.Since(start).Milliseconds()
+	timeElapsed := time.Since(start)
 
 	httpStatus := resp.Status
 
 	resp.Body.Close()
 
-	return fmt.Sprintf("ret_msg:%s,ret_time:%d", httpStatus, timeElapsed), nil
-}
\ No newline at end of file
+	return timeElapsed, httpStatus, nil
+}
@@ -0,0 +1,201 @@
+package all
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"sort"
+	"time"
+
+	"github.com/quic-go/quic-go"
+
+	v2net "github.com/xtls/xray-core/common/net"
+	core "github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/features/dns"
+)
+
+// probeStats summarizes a run of several samples against the same
+// (target, outbound, proto) combination.
+type probeStats struct {
+	Sent   int
+	Lost   int
+	Min    time.Duration
+	Avg    time.Duration
+	Max    time.Duration
+	P95    time.Duration
+	Status string
+	Error  string
+}
+
+func summarize(samples []time.Duration, sent int, status, lastErr string) probeStats {
+	s := probeStats{Sent: sent, Lost: sent - len(samples), Status: status, Error: lastErr}
+	if len(samples) == 0 {
+		return s
+	}
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+	s.Min = sorted[0]
+	s.Max = sorted[len(sorted)-1]
+	s.Avg = total / time.Duration(len(sorted))
+	// Nearest-rank percentile: the ceil(0.95*n)-th smallest sample.
+	p95idx := (len(sorted)*95+99)/100 - 1
+	if p95idx >= len(sorted) {
+		p95idx = len(sorted) - 1
+	}
+	s.P95 = sorted[p95idx]
+	return s
+}
+
+// probeOnce performs a single probe of the given protocol against target,
+// through outbound tag (empty for default routing).
+func probeOnce(ctx context.Context, inst *core.Instance, proto, target, tag string, timeout time.Duration) (time.Duration, string, error) {
+	switch proto {
+	case "", "http":
+		return measureInstDelay(ctx, inst, target, tag, timeout.String())
+	case "tcp":
+		return probeTCP(ctx, inst, target, tag, timeout)
+	case "tls":
+		return probeTLS(ctx, inst, target, tag, timeout)
+	case "udp":
+		return probeUDP(ctx, inst, target, tag, timeout)
+	case "quic":
+		return probeQUIC(ctx, inst, target, tag, timeout)
+	case "dns":
+		return probeDNS(ctx, inst, target, tag, timeout)
+	default:
+		return 0, "", fmt.Errorf("unknown -proto %q", proto)
+	}
+}
+
+func dialDestination(ctx context.Context, inst *core.Instance, network, addr, tag string) (net.Conn, error) {
+	dest, err := v2net.ParseDestination(fmt.Sprintf("%s:%s", network, addr))
+	if err != nil {
+		return nil, err
+	}
+	return core.DialTagged(ctx, inst, dest, tag)
+}
+
+// probeTCP measures the time to complete a TCP three-way handshake through
+// the instance.
+func probeTCP(ctx context.Context, inst *core.Instance, target, tag string, timeout time.Duration) (time.Duration, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	conn, err := dialDestination(ctx, inst, "tcp", target, tag)
+	if err != nil {
+		return 0, "", err
+	}
+	defer conn.Close()
+	return time.Since(start), "connected", nil
+}
+
+// probeTLS measures handshake time on top of a TCP connection through the
+// instance.
+func probeTLS(ctx context.Context, inst *core.Instance, target, tag string, timeout time.Duration) (time.Duration, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	host, _, err := net.SplitHostPort(target)
+	if err != nil {
+		host = target
+	}
+
+	start := time.Now()
+	conn, err := dialDestination(ctx, inst, "tcp", target, tag)
+	if err != nil {
+		return 0, "", err
+	}
+	defer conn.Close()
+
+	tlsConn := tls.Client(conn, &tls.Config{ServerName: host, InsecureSkipVerify: true})
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		return 0, "", err
+	}
+	return time.Since(start), tls.VersionName(tlsConn.ConnectionState().Version), nil
+}
+
+// probeUDP measures the time to dial target through the instance and hand
+// it a single datagram. No reply is awaited, so it does not show whether
+// the outbound or the target received the datagram.
+func probeUDP(ctx context.Context, inst *core.Instance, target, tag string, timeout time.Duration) (time.Duration, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	conn, err := dialDestination(ctx, inst, "udp", target, tag)
+	if err != nil {
+		return 0, "", err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte{0}); err != nil {
+		return 0, "", err
+	}
+	return time.Since(start), "sent", nil
+}
+
+// probeQUIC measures the time to complete a QUIC handshake through the
+// instance's UDP transport.
+func probeQUIC(ctx context.Context, inst *core.Instance, target, tag string, timeout time.Duration) (time.Duration, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	host, _, err := net.SplitHostPort(target)
+	if err != nil {
+		host = target
+	}
+
+	dest, err := v2net.ParseDestination(fmt.Sprintf("udp:%s", target))
+	if err != nil {
+		return 0, "", err
+	}
+
+	start := time.Now()
+	packetConn, err := core.DialUDP(ctx, inst, dest, tag)
+	if err != nil {
+		return 0, "", err
+	}
+	defer packetConn.Close()
+
+	udpAddr, err := net.ResolveUDPAddr("udp", target)
+	if err != nil {
+		return 0, "", err
+	}
+
+	tr := &quic.Transport{Conn: packetConn}
+	sess, err := tr.Dial(ctx, udpAddr, &tls.Config{
+		ServerName: host, InsecureSkipVerify: true, NextProtos: []string{"h3"},
+	}, nil)
+	if err != nil {
+		return 0, "", err
+	}
+	defer sess.CloseWithError(0, "")
+	return time.Since(start), "handshake complete", nil
+}
+
+// probeDNS issues a resolver query through the instance's DNS app and
+// times the round trip.
+func probeDNS(ctx context.Context, inst *core.Instance, target, tag string, timeout time.Duration) (time.Duration, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	dnsClient := inst.GetFeature((*core.DNSClient)(nil))
+	client, ok := dnsClient.(core.DNSClient)
+	if !ok {
+		return 0, "", fmt.Errorf("no dns client available in this config")
+	}
+
+	start := time.Now()
+	ips, err := client.LookupIP(target, dns.IPOption{IPv4Enable: true, IPv6Enable: true})
+	if err != nil {
+		return 0, "", err
+	}
+	return time.Since(start), fmt.Sprintf("%d record(s)", len(ips)), nil
+}